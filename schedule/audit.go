@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/target/goalert/permission"
+	"github.com/target/goalert/validation/validate"
+)
+
+// AuditOp identifies the kind of mutation recorded in a ScheduleDataAudit entry.
+type AuditOp string
+
+// Recognized AuditOp values.
+const (
+	AuditOpSet    AuditOp = "set"
+	AuditOpClear  AuditOp = "clear"
+	AuditOpRevert AuditOp = "revert"
+)
+
+// ScheduleDataAudit records a single mutation of a schedule's TemporarySchedules, so
+// operators can answer "who changed my coverage" and, if needed, undo it.
+type ScheduleDataAudit struct {
+	ID         string
+	ScheduleID string
+
+	// Actor is the permission.User ID that performed the mutation, empty if unavailable.
+	Actor     string
+	Timestamp time.Time
+
+	Op         AuditOp
+	Start, End time.Time // the time window the mutation targeted
+
+	// Before and After are the JSON encodings of data.V1 (both the materialized
+	// TemporarySchedules and the RecurringSchedule rules behind them) immediately before
+	// and after the mutation was applied, so Revert can restore recurring-schedule state
+	// (including MaterializedThrough and Excluded windows), not just the materialized list.
+	Before json.RawMessage
+	After  json.RawMessage
+}
+
+// recordAudit inserts a ScheduleDataAudit row for a mutation within tx. The generated ID
+// and timestamp are not returned.
+func (store *Store) recordAudit(ctx context.Context, tx *sql.Tx, scheduleID string, op AuditOp, window TimeRange, before, after DataV1) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.StmtContext(ctx, store.insertAudit).ExecContext(ctx,
+		scheduleID, permission.UserID(ctx), op, window.Start, window.End, beforeJSON, afterJSON,
+	)
+	return err
+}
+
+// TemporaryScheduleHistory returns the audit records for scheduleID with a Timestamp
+// between since and until, most recent first.
+func (store *Store) TemporaryScheduleHistory(ctx context.Context, scheduleID string, since, until time.Time) ([]ScheduleDataAudit, error) {
+	err := permission.LimitCheckAny(ctx, permission.User)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validate.UUID("ScheduleID", scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := store.findAuditHistory.QueryContext(ctx, scheduleID, since, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []ScheduleDataAudit
+	for rows.Next() {
+		var a ScheduleDataAudit
+		err = rows.Scan(&a.ID, &a.ScheduleID, &a.Actor, &a.Op, &a.Start, &a.End, &a.Before, &a.After, &a.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, a)
+	}
+
+	return result, rows.Err()
+}
+
+// Revert replays the inverse of the mutation recorded by auditID -- restoring data.V1
+// (both TemporarySchedules and RecurringSchedules) to its Before state -- as a new,
+// audited mutation. It returns the resulting set of TemporarySchedules (post-merge,
+// post-user-filter).
+func (store *Store) Revert(ctx context.Context, auditID string) ([]TemporarySchedule, error) {
+	err := permission.LimitCheckAny(ctx, permission.User)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validate.UUID("AuditID", auditID)
+	if err != nil {
+		return nil, err
+	}
+
+	var a ScheduleDataAudit
+	err = store.findAuditByID.QueryRowContext(ctx, auditID).Scan(
+		&a.ID, &a.ScheduleID, &a.Actor, &a.Op, &a.Start, &a.End, &a.Before, &a.After, &a.Timestamp,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var before DataV1
+	err = json.Unmarshal(a.Before, &before)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.updateFixedShifts(ctx, nil, a.ScheduleID, -1, AuditOpRevert, TimeRange{Start: a.Start, End: a.End}, func(data *Data) error {
+		data.V1 = before
+		return nil
+	})
+}
@@ -0,0 +1,15 @@
+package schedule
+
+import "time"
+
+// Clock provides the current time to a Store. It exists so tests (and future
+// what-if/planning features) can drive schedule logic against a simulated wall clock
+// instead of sleeping and waiting on time.Now.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock, backed by time.Now.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
@@ -0,0 +1,22 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (f fakeClock) Now() time.Time { return f.now }
+
+func TestValidateFuture(t *testing.T) {
+	now := tm(0)
+	store := &Store{clock: fakeClock{now: now}}
+
+	assert.NoError(t, store.validateFuture("End", now.Add(6*time.Minute)))
+	assert.Error(t, store.validateFuture("End", now.Add(5*time.Minute)))
+	assert.Error(t, store.validateFuture("End", now))
+	assert.Error(t, store.validateFuture("End", now.Add(-time.Hour)))
+}
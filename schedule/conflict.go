@@ -0,0 +1,24 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrVersionConflict is returned when an IfMatch version does not match the
+// currently stored Data.Version, indicating a lost update.
+var ErrVersionConflict = errors.New("schedule: version conflict")
+
+// retryOnConflict calls fn up to n times, stopping as soon as fn returns a nil error
+// or an error other than ErrVersionConflict. It is used to re-read and re-apply an
+// optimistic update after a concurrent writer wins the race.
+func retryOnConflict(ctx context.Context, n int, fn func() error) error {
+	var err error
+	for i := 0; i < n; i++ {
+		err = fn()
+		if err == nil || !errors.Is(err, ErrVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
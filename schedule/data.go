@@ -0,0 +1,19 @@
+package schedule
+
+// Data contains the schedule_data document for a schedule.
+type Data struct {
+	// Version is incremented on every update, and is used to detect lost updates
+	// (optimistic concurrency) between concurrent writers.
+	Version int
+
+	V1 DataV1
+}
+
+// DataV1 is version 1 of the schedule data document.
+type DataV1 struct {
+	TemporarySchedules []TemporarySchedule
+
+	// RecurringSchedules holds the rules behind recurring TemporarySchedules, so that
+	// TemporarySchedules can lazily derive further occurrences as time advances.
+	RecurringSchedules []RecurringSchedule
+}
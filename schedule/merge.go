@@ -0,0 +1,114 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// MergeTemporarySchedules sorts and merges adjacent or overlapping TemporarySchedules,
+// combining their Shifts. Two schedules are only merged when they share an identical
+// set of Tags, so operators can keep distinct coverage windows (e.g. different incidents)
+// from collapsing into one.
+func MergeTemporarySchedules(schedules []TemporarySchedule) []TemporarySchedule {
+	if len(schedules) == 0 {
+		return schedules
+	}
+
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Start.Before(schedules[j].Start) })
+
+	merged := schedules[:1]
+	for _, t := range schedules[1:] {
+		// compare against the latest merged schedule with the same tags, so a differently
+		// tagged schedule in between does not prevent merging
+		var last *TemporarySchedule
+		for i := len(merged) - 1; i >= 0; i-- {
+			if sameTags(merged[i].Tags, t.Tags) {
+				last = &merged[i]
+				break
+			}
+		}
+		if last != nil && !t.Start.After(last.End) {
+			if t.End.After(last.End) {
+				last.End = t.End
+			}
+			last.Shifts = append(last.Shifts, t.Shifts...)
+			continue
+		}
+		merged = append(merged, t)
+	}
+
+	return merged
+}
+
+// sameTags reports whether a and b contain the same set of tags, ignoring order.
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	aCopy := append([]string{}, a...)
+	bCopy := append([]string{}, b...)
+	sort.Strings(aCopy)
+	sort.Strings(bCopy)
+	for i := range aCopy {
+		if aCopy[i] != bCopy[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setFixedShifts replaces any existing coverage between temp.Start and temp.End with temp,
+// splitting or dropping TemporarySchedules that currently overlap that range.
+func setFixedShifts(existing []TemporarySchedule, temp TemporarySchedule) []TemporarySchedule {
+	result := deleteFixedShifts(existing, temp.Start, temp.End)
+	result = append(result, temp)
+	return MergeTemporarySchedules(result)
+}
+
+// deleteFixedShifts removes (splitting where necessary) any TemporarySchedules that
+// intersect the range defined by start and end.
+func deleteFixedShifts(existing []TemporarySchedule, start, end time.Time) []TemporarySchedule {
+	result := make([]TemporarySchedule, 0, len(existing))
+	for _, t := range existing {
+		if !t.Start.Before(end) || !t.End.After(start) {
+			// no overlap with [start, end)
+			result = append(result, t)
+			continue
+		}
+
+		if t.Start.Before(start) {
+			result = append(result, clipTemporarySchedule(t, t.Start, start))
+		}
+		if t.End.After(end) {
+			result = append(result, clipTemporarySchedule(t, end, t.End))
+		}
+	}
+
+	return result
+}
+
+// clipTemporarySchedule returns a copy of t restricted to the range [start, end),
+// trimming or dropping any Shifts that fall outside of it.
+func clipTemporarySchedule(t TemporarySchedule, start, end time.Time) TemporarySchedule {
+	clipped := t
+	clipped.Start, clipped.End = start, end
+
+	shifts := make([]Shift, 0, len(t.Shifts))
+	for _, s := range t.Shifts {
+		if !s.Start.Before(end) || !s.End.After(start) {
+			continue
+		}
+		if s.Start.Before(start) {
+			s.Start = start
+		}
+		if s.End.After(end) {
+			s.End = end
+		}
+		shifts = append(shifts, s)
+	}
+	clipped.Shifts = shifts
+
+	return clipped
+}
@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tm(hour int) time.Time {
+	return time.Date(2024, 6, 1, hour, 0, 0, 0, time.UTC)
+}
+
+func TestMergeTemporarySchedules(t *testing.T) {
+	in := []TemporarySchedule{
+		{Start: tm(4), End: tm(6), Tags: []string{"a"}},
+		{Start: tm(0), End: tm(2), Tags: []string{"a"}},
+		{Start: tm(2), End: tm(4), Tags: []string{"a"}},
+		{Start: tm(0), End: tm(2), Tags: []string{"b"}},
+	}
+
+	result := MergeTemporarySchedules(in)
+
+	assert.Len(t, result, 2)
+	assert.Equal(t, tm(0), result[0].Start)
+	assert.Equal(t, tm(6), result[0].End)
+	assert.Equal(t, []string{"a"}, result[0].Tags)
+	assert.Equal(t, []string{"b"}, result[1].Tags)
+}
+
+func TestSameTags(t *testing.T) {
+	assert.True(t, sameTags([]string{"a", "b"}, []string{"b", "a"}))
+	assert.False(t, sameTags([]string{"a"}, []string{"a", "b"}))
+	assert.False(t, sameTags([]string{"a"}, []string{"b"}))
+}
+
+func TestDeleteFixedShifts_NoAliasing(t *testing.T) {
+	existing := []TemporarySchedule{
+		{Start: tm(0), End: tm(4), Shifts: []Shift{{UserID: "u1", Start: tm(0), End: tm(4)}}},
+	}
+
+	result := deleteFixedShifts(existing, tm(1), tm(2))
+
+	// existing must be untouched; result is clipped around the deleted window
+	assert.Equal(t, tm(0), existing[0].Start)
+	assert.Equal(t, tm(4), existing[0].End)
+
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, tm(0), result[0].Start)
+		assert.Equal(t, tm(1), result[0].End)
+		assert.Equal(t, tm(2), result[1].Start)
+		assert.Equal(t, tm(4), result[1].End)
+	}
+}
+
+func TestClipTemporarySchedule(t *testing.T) {
+	in := TemporarySchedule{
+		Start: tm(0), End: tm(4),
+		Shifts: []Shift{
+			{UserID: "u1", Start: tm(0), End: tm(2)},
+			{UserID: "u2", Start: tm(2), End: tm(4)},
+		},
+	}
+
+	clipped := clipTemporarySchedule(in, tm(1), tm(3))
+
+	assert.Equal(t, tm(1), clipped.Start)
+	assert.Equal(t, tm(3), clipped.End)
+	if assert.Len(t, clipped.Shifts, 2) {
+		assert.Equal(t, tm(1), clipped.Shifts[0].Start)
+		assert.Equal(t, tm(2), clipped.Shifts[0].End)
+		assert.Equal(t, tm(2), clipped.Shifts[1].Start)
+		assert.Equal(t, tm(3), clipped.Shifts[1].End)
+	}
+
+	// original input must be unmodified
+	assert.Equal(t, tm(0), in.Shifts[0].Start)
+}
+
+func TestSetFixedShifts(t *testing.T) {
+	existing := []TemporarySchedule{
+		{Start: tm(0), End: tm(4), Tags: []string{"a"}},
+	}
+
+	result := setFixedShifts(existing, TemporarySchedule{Start: tm(2), End: tm(6), Tags: []string{"b"}})
+
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, tm(0), result[0].Start)
+		assert.Equal(t, tm(2), result[0].End)
+		assert.Equal(t, tm(2), result[1].Start)
+		assert.Equal(t, tm(6), result[1].End)
+	}
+}
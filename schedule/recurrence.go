@@ -0,0 +1,213 @@
+package schedule
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/target/goalert/validation"
+)
+
+// recurrenceHorizon bounds how far into the future a Recurrence will be expanded in a
+// single pass, regardless of its COUNT: from its anchor Start when it is first set, and from
+// the Store's current time when TemporarySchedules lazily expands it.
+const recurrenceHorizon = 90 * 24 * time.Hour
+
+// Recurrence defines how a TemporarySchedule repeats, so operators can define coverage
+// like "every Friday 18:00-22:00 for the next 12 weeks" without submitting one
+// TemporarySchedule per occurrence.
+type Recurrence struct {
+	// RRule is a standard RRULE string, e.g. "FREQ=WEEKLY;BYDAY=FR;COUNT=12".
+	// Only FREQ=WEEKLY is currently supported.
+	RRule string
+
+	// Duration is the length of each occurrence, added to its start time to get its end.
+	Duration time.Duration
+}
+
+// TimeRange is a half-open [Start, End) span of time.
+type TimeRange struct{ Start, End time.Time }
+
+// RecurringSchedule is the persisted rule behind a recurring TemporarySchedule: the anchor
+// (first) occurrence plus the Recurrence used to derive later ones. It is stored alongside
+// the already-materialized TemporarySchedules so TemporarySchedules can lazily expand further
+// occurrences as time advances, without re-generating ones already persisted.
+type RecurringSchedule struct {
+	// Anchor describes the first occurrence: its Start, Shifts (as offsets from Start) and Tags
+	// are reused, shifted, for every later occurrence.
+	Anchor TemporarySchedule
+
+	Recurrence Recurrence
+
+	// MaterializedThrough is the end of the last occurrence already persisted as a
+	// TemporarySchedule; lazy expansion only derives occurrences starting at or after it.
+	MaterializedThrough time.Time
+
+	// Excluded holds windows cleared by ClearTemporarySchedules; any occurrence overlapping
+	// one is skipped, without altering the underlying rule.
+	Excluded []TimeRange
+}
+
+// expand returns the occurrences of rs starting at or after from, up to horizon or maxCount,
+// whichever comes first, skipping any that fall in an excluded window.
+func (rs RecurringSchedule) expand(from, horizon time.Time, maxCount int) []TemporarySchedule {
+	spec, err := parseRRule(rs.Recurrence.RRule)
+	if err != nil {
+		return nil
+	}
+
+	anchor := rs.Anchor.Start
+	starts := spec.occurrenceStarts(anchor, horizon, maxCount)
+
+	var result []TemporarySchedule
+	for _, s := range starts {
+		if s.Before(from) {
+			continue
+		}
+		e := s.Add(rs.Recurrence.Duration)
+		if rangesOverlapAny(rs.Excluded, s, e) {
+			continue
+		}
+
+		delta := s.Sub(anchor)
+		shifts := make([]Shift, len(rs.Anchor.Shifts))
+		for i, sh := range rs.Anchor.Shifts {
+			shifts[i] = Shift{UserID: sh.UserID, Start: sh.Start.Add(delta), End: sh.End.Add(delta)}
+		}
+
+		result = append(result, TemporarySchedule{
+			Start:  s,
+			End:    e,
+			Shifts: shifts,
+			Tags:   rs.Anchor.Tags,
+		})
+	}
+
+	return result
+}
+
+func rangesOverlapAny(ranges []TimeRange, start, end time.Time) bool {
+	for _, r := range ranges {
+		if start.Before(r.End) && end.After(r.Start) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRecurrence checks that rec has a supported, parseable RRule and a positive Duration
+// that matches occurrenceDuration (the literal Start/End window submitted for the anchor
+// occurrence). Every later occurrence is shifted, not reclipped, from the anchor's Shifts, so a
+// mismatched Duration would persist TemporarySchedules whose Shifts fall outside their own
+// Start/End.
+func validateRecurrence(fieldName string, rec *Recurrence, occurrenceDuration time.Duration) error {
+	if rec.Duration <= 0 {
+		return validation.NewFieldError(fieldName+".Duration", "must be positive")
+	}
+	if rec.Duration != occurrenceDuration {
+		return validation.NewFieldError(fieldName+".Duration", "must equal End-Start")
+	}
+
+	_, err := parseRRule(rec.RRule)
+	return err
+}
+
+type rruleSpec struct {
+	interval int
+	byDay    []time.Weekday
+	count    int // 0 means unbounded (caller still bounds by horizon/maxCount)
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseRRule parses a (restricted) subset of RFC 5545 RRULE: FREQ=WEEKLY, with optional
+// INTERVAL, BYDAY and COUNT components.
+func parseRRule(s string) (rruleSpec, error) {
+	spec := rruleSpec{interval: 1}
+	var sawFreq bool
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return rruleSpec{}, validation.NewFieldError("Recurrence.RRule", "invalid component: "+part)
+		}
+		key, val := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		switch key {
+		case "FREQ":
+			if val != "WEEKLY" {
+				return rruleSpec{}, validation.NewFieldError("Recurrence.RRule", "only FREQ=WEEKLY is supported")
+			}
+			sawFreq = true
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rruleSpec{}, validation.NewFieldError("Recurrence.RRule", "invalid INTERVAL")
+			}
+			spec.interval = n
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				wd, ok := rruleWeekdays[d]
+				if !ok {
+					return rruleSpec{}, validation.NewFieldError("Recurrence.RRule", "invalid BYDAY value: "+d)
+				}
+				spec.byDay = append(spec.byDay, wd)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return rruleSpec{}, validation.NewFieldError("Recurrence.RRule", "invalid COUNT")
+			}
+			spec.count = n
+		default:
+			return rruleSpec{}, validation.NewFieldError("Recurrence.RRule", "unsupported component: "+key)
+		}
+	}
+	if !sawFreq {
+		return rruleSpec{}, validation.NewFieldError("Recurrence.RRule", "FREQ is required")
+	}
+
+	return spec, nil
+}
+
+// occurrenceStarts returns the start times generated by spec, anchored at anchor, up to
+// horizon or maxCount, whichever comes first.
+func (spec rruleSpec) occurrenceStarts(anchor, horizon time.Time, maxCount int) []time.Time {
+	days := append([]time.Weekday{}, spec.byDay...)
+	if len(days) == 0 {
+		days = []time.Weekday{anchor.Weekday()}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+
+	limit := maxCount
+	if spec.count > 0 && spec.count < limit {
+		limit = spec.count
+	}
+
+	weekStart := anchor.AddDate(0, 0, -int(anchor.Weekday()))
+
+	var starts []time.Time
+	for week := 0; week < 520 && len(starts) < limit; week += spec.interval {
+		for _, d := range days {
+			t := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day()+week*7+int(d),
+				anchor.Hour(), anchor.Minute(), anchor.Second(), anchor.Nanosecond(), anchor.Location())
+			if t.Before(anchor) {
+				continue
+			}
+			if t.After(horizon) {
+				return starts
+			}
+			starts = append(starts, t)
+			if len(starts) >= limit {
+				break
+			}
+		}
+	}
+
+	return starts
+}
@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRRule(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		spec, err := parseRRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=5")
+		require.NoError(t, err)
+		assert.Equal(t, 2, spec.interval)
+		assert.Equal(t, 5, spec.count)
+		assert.Equal(t, []time.Weekday{time.Monday, time.Wednesday}, spec.byDay)
+	})
+
+	for _, s := range []string{
+		"FREQ=DAILY",
+		"INTERVAL=1",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;COUNT=0",
+		"FREQ=WEEKLY;INTERVAL=0",
+		"FREQ=WEEKLY;FOO=BAR",
+	} {
+		t.Run("invalid/"+s, func(t *testing.T) {
+			_, err := parseRRule(s)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestOccurrenceStarts(t *testing.T) {
+	// anchor is a Saturday
+	anchor := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	spec, err := parseRRule("FREQ=WEEKLY;BYDAY=SA;COUNT=3")
+	require.NoError(t, err)
+
+	starts := spec.occurrenceStarts(anchor, anchor.AddDate(1, 0, 0), 10)
+
+	require.Len(t, starts, 3)
+	for i, s := range starts {
+		assert.Equal(t, anchor.AddDate(0, 0, 7*i), s)
+	}
+}
+
+func TestOccurrenceStarts_HorizonBound(t *testing.T) {
+	anchor := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	spec, err := parseRRule("FREQ=WEEKLY;BYDAY=SA")
+	require.NoError(t, err)
+
+	horizon := anchor.AddDate(0, 0, 14)
+	starts := spec.occurrenceStarts(anchor, horizon, 100)
+
+	// only occurrences on/before the horizon should be returned
+	for _, s := range starts {
+		assert.False(t, s.After(horizon))
+	}
+	assert.Len(t, starts, 3)
+}
+
+func TestRecurringSchedule_Expand(t *testing.T) {
+	anchor := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	rs := RecurringSchedule{
+		Anchor: TemporarySchedule{
+			Start: anchor,
+			End:   anchor.Add(4 * time.Hour),
+			Shifts: []Shift{
+				{UserID: "u1", Start: anchor, End: anchor.Add(4 * time.Hour)},
+			},
+			Tags: []string{"oncall"},
+		},
+		Recurrence: Recurrence{RRule: "FREQ=WEEKLY;BYDAY=SA;COUNT=4", Duration: 4 * time.Hour},
+	}
+
+	occ := rs.expand(anchor, anchor.AddDate(1, 0, 0), 10)
+	require.Len(t, occ, 4)
+
+	second := occ[1]
+	assert.Equal(t, anchor.AddDate(0, 0, 7), second.Start)
+	assert.Equal(t, anchor.AddDate(0, 0, 7).Add(4*time.Hour), second.End)
+	assert.Equal(t, []string{"oncall"}, second.Tags)
+	require.Len(t, second.Shifts, 1)
+	assert.Equal(t, second.Start, second.Shifts[0].Start)
+}
+
+func TestRecurringSchedule_Expand_Excluded(t *testing.T) {
+	anchor := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	rs := RecurringSchedule{
+		Anchor: TemporarySchedule{
+			Start: anchor,
+			End:   anchor.Add(4 * time.Hour),
+		},
+		Recurrence: Recurrence{RRule: "FREQ=WEEKLY;BYDAY=SA;COUNT=3", Duration: 4 * time.Hour},
+		Excluded: []TimeRange{
+			{Start: anchor.AddDate(0, 0, 7), End: anchor.AddDate(0, 0, 7).Add(4 * time.Hour)},
+		},
+	}
+
+	occ := rs.expand(anchor, anchor.AddDate(1, 0, 0), 10)
+
+	require.Len(t, occ, 2)
+	assert.Equal(t, anchor, occ[0].Start)
+	assert.Equal(t, anchor.AddDate(0, 0, 14), occ[1].Start)
+}
+
+func TestRangesOverlapAny(t *testing.T) {
+	ranges := []TimeRange{{Start: tm(1), End: tm(2)}}
+
+	assert.True(t, rangesOverlapAny(ranges, tm(0), tm(2)))
+	assert.False(t, rangesOverlapAny(ranges, tm(2), tm(3)))
+}
@@ -0,0 +1,63 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/target/goalert/user"
+	"github.com/target/goalert/util"
+)
+
+// Store allows the lookup and management of a schedule's stored data (temporary schedules, etc.).
+type Store struct {
+	db    *sql.DB
+	usr   *user.Store
+	clock Clock
+
+	findData   *sql.Stmt
+	insertData *sql.Stmt
+	updateData *sql.Stmt
+
+	insertAudit      *sql.Stmt
+	findAuditHistory *sql.Stmt
+	findAuditByID    *sql.Stmt
+}
+
+// NewStore will create a new Store for schedule data, using the provided DB.
+func NewStore(ctx context.Context, db *sql.DB, usr *user.Store) (*Store, error) {
+	return NewStoreWithClock(ctx, db, usr, systemClock{})
+}
+
+// NewStoreWithClock creates a new Store that consults clock for the current time,
+// instead of time.Now, when validating and truncating temporary schedules.
+func NewStoreWithClock(ctx context.Context, db *sql.DB, usr *user.Store, clock Clock) (*Store, error) {
+	p := &util.Prepare{Ctx: ctx, DB: db}
+
+	return &Store{
+		db:    db,
+		usr:   usr,
+		clock: clock,
+
+		findData:   p.P(`SELECT data FROM schedule_data WHERE schedule_id = $1`),
+		insertData: p.P(`INSERT INTO schedule_data (schedule_id) VALUES ($1)`),
+		// optimistic concurrency: only update if the row's Version still matches what we read
+		updateData: p.P(`UPDATE schedule_data SET data = $2 WHERE schedule_id = $1 AND coalesce((data->>'Version')::int, 0) = $3`),
+
+		insertAudit: p.P(`
+			INSERT INTO schedule_data_audit (schedule_id, actor_user_id, op, start_time, end_time, before_data, after_data)
+			VALUES ($1, nullif($2, '')::uuid, $3, $4, $5, $6, $7)
+			RETURNING id, timestamp
+		`),
+		findAuditHistory: p.P(`
+			SELECT id, schedule_id, coalesce(actor_user_id::text, ''), op, start_time, end_time, before_data, after_data, timestamp
+			FROM schedule_data_audit
+			WHERE schedule_id = $1 AND timestamp >= $2 AND timestamp <= $3
+			ORDER BY timestamp DESC
+		`),
+		findAuditByID: p.P(`
+			SELECT id, schedule_id, coalesce(actor_user_id::text, ''), op, start_time, end_time, before_data, after_data, timestamp
+			FROM schedule_data_audit
+			WHERE id = $1
+		`),
+	}, p.Err
+}
@@ -49,13 +49,26 @@ func (store *Store) TemporarySchedules(ctx context.Context, tx *sql.Tx, schedule
 		}
 	}
 
+	return store.mergedTemporarySchedules(ctx, data)
+}
+
+// mergedTemporarySchedules applies the same post-processing TemporarySchedules does
+// (lazily expanding recurring schedules, dropping shifts for non-existent users, then merging)
+// to data.V1.TemporarySchedules.
+func (store *Store) mergedTemporarySchedules(ctx context.Context, data Data) ([]TemporarySchedule, error) {
 	check, err := store.usr.UserExists(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	all := data.V1.TemporarySchedules
+	horizon := store.clock.Now().Add(recurrenceHorizon)
+	for _, rs := range data.V1.RecurringSchedules {
+		all = append(all, rs.expand(rs.MaterializedThrough, horizon, FixedShiftsPerTemporaryScheduleLimit)...)
+	}
+
 	// omit shifts for non-existant users
-	for i, tmp := range data.V1.TemporarySchedules {
+	for i, tmp := range all {
 		shifts := tmp.Shifts[:0]
 		for _, shift := range tmp.Shifts {
 			if !check.UserExistsString(shift.UserID) {
@@ -64,12 +77,10 @@ func (store *Store) TemporarySchedules(ctx context.Context, tx *sql.Tx, schedule
 			shifts = append(shifts, shift)
 		}
 		tmp.Shifts = shifts
-		data.V1.TemporarySchedules[i] = tmp
+		all[i] = tmp
 	}
 
-	data.V1.TemporarySchedules = MergeTemporarySchedules(data.V1.TemporarySchedules)
-
-	return data.V1.TemporarySchedules, nil
+	return MergeTemporarySchedules(all), nil
 }
 
 func isDataPkeyConflict(err error) bool {
@@ -79,74 +90,124 @@ func isDataPkeyConflict(err error) bool {
 	}
 	return dbErr.ConstraintName == "schedule_data_pkey"
 }
-func (store *Store) updateFixedShifts(ctx context.Context, tx *sql.Tx, scheduleID string, apply func(data *Data) error) error {
-	var err error
-	externalTx := tx != nil
-	if !externalTx {
-		tx, err = store.db.BeginTx(ctx, nil)
+
+// updateFixedShifts applies the given function to the schedule's Data, persists the result
+// using an optimistic-concurrency check against Data.Version, records a ScheduleDataAudit entry
+// for the mutation, and returns the resulting TemporarySchedules (post-merge, post-user-filter)
+// as TemporarySchedules would.
+//
+// op and window describe the mutation for the audit log. If ifMatch is >= 0, the update is
+// rejected with ErrVersionConflict unless the stored Version equals ifMatch. If tx is nil, a lost
+// race against another writer (ErrVersionConflict) is retried from scratch, re-reading and
+// re-applying apply, up to a few times.
+func (store *Store) updateFixedShifts(ctx context.Context, tx *sql.Tx, scheduleID string, ifMatch int, op AuditOp, window TimeRange, apply func(data *Data) error) ([]TemporarySchedule, error) {
+	if tx != nil {
+		return store.updateFixedShiftsTx(ctx, tx, scheduleID, ifMatch, op, window, apply)
+	}
+
+	var result []TemporarySchedule
+	err := retryOnConflict(ctx, 5, func() error {
+		tx, err := store.db.BeginTx(ctx, nil)
 		if err != nil {
 			return err
 		}
 		defer tx.Rollback()
+
+		result, err = store.updateFixedShiftsTx(ctx, tx, scheduleID, ifMatch, op, window, apply)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return result, nil
+}
+
+func (store *Store) updateFixedShiftsTx(ctx context.Context, tx *sql.Tx, scheduleID string, ifMatch int, op AuditOp, window TimeRange, apply func(data *Data) error) ([]TemporarySchedule, error) {
 	var rawData json.RawMessage
-	// Select for update, if it does not exist try inserting, if that fails due to a race, re-try select for update
-	err = tx.StmtContext(ctx, store.findUpdData).QueryRowContext(ctx, scheduleID).Scan(&rawData)
+	err := tx.StmtContext(ctx, store.findData).QueryRowContext(ctx, scheduleID).Scan(&rawData)
 	if err == sql.ErrNoRows {
 		_, err = tx.StmtContext(ctx, store.insertData).ExecContext(ctx, scheduleID)
 		if isDataPkeyConflict(err) {
-			// insert happened after orig. select for update and our subsequent insert, re-try select for update
-			err = tx.StmtContext(ctx, store.findUpdData).QueryRowContext(ctx, scheduleID).Scan(&rawData)
+			// insert happened after our orig. select and our subsequent insert, re-try the select
+			err = tx.StmtContext(ctx, store.findData).QueryRowContext(ctx, scheduleID).Scan(&rawData)
 		}
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var data Data
 	if len(rawData) > 0 {
 		err = json.Unmarshal(rawData, &data)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	if ifMatch >= 0 && ifMatch != data.Version {
+		return nil, ErrVersionConflict
+	}
+	expectVersion := data.Version
+	before := data.V1
+
 	err = apply(&data)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	data.Version = expectVersion + 1
 
 	// preserve unknown fields
 	rawData, err = jsonutil.Apply(rawData, data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = tx.StmtContext(ctx, store.updateData).ExecContext(ctx, scheduleID, rawData)
+	res, err := tx.StmtContext(ctx, store.updateData).ExecContext(ctx, scheduleID, rawData, expectVersion)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrVersionConflict
 	}
 
-	if !externalTx {
-		return tx.Commit()
+	err = store.recordAudit(ctx, tx, scheduleID, op, window, before, data.V1)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return store.mergedTemporarySchedules(ctx, data)
 }
 
-func validateFuture(fieldName string, t time.Time) error {
-	if time.Until(t) > 5*time.Minute {
+func (store *Store) validateFuture(fieldName string, t time.Time) error {
+	if t.Sub(store.clock.Now()) > 5*time.Minute {
 		return nil
 	}
 	return validation.NewFieldError(fieldName, "must be at least 5 min the future")
 }
 
-// SetTemporarySchedule will cause the schedule to use only, and exactly, the provided set of shifts between the provided start and end times.
-func (store *Store) SetTemporarySchedule(ctx context.Context, tx *sql.Tx, scheduleID string, temp TemporarySchedule) error {
+// SetTemporarySchedule will cause the schedule to use only, and exactly, the provided set of shifts
+// between the provided start and end times. It returns the resulting set of TemporarySchedules
+// (post-merge, post-user-filter) as TemporarySchedules would.
+func (store *Store) SetTemporarySchedule(ctx context.Context, tx *sql.Tx, scheduleID string, temp TemporarySchedule) ([]TemporarySchedule, error) {
+	return store.SetTemporaryScheduleIfMatch(ctx, tx, scheduleID, temp, -1)
+}
+
+// SetTemporaryScheduleIfMatch behaves like SetTemporarySchedule, but fails with ErrVersionConflict
+// if the schedule's stored Data.Version does not equal ifMatch. Passing a negative ifMatch disables
+// the check, matching SetTemporarySchedule. This lets API clients detect (and react to) a lost update.
+func (store *Store) SetTemporaryScheduleIfMatch(ctx context.Context, tx *sql.Tx, scheduleID string, temp TemporarySchedule, ifMatch int) ([]TemporarySchedule, error) {
 	err := permission.LimitCheckAny(ctx, permission.User)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	temp.Start = temp.Start.Truncate(time.Minute)
 	temp.End = temp.End.Truncate(time.Minute)
@@ -156,44 +217,158 @@ func (store *Store) SetTemporarySchedule(ctx context.Context, tx *sql.Tx, schedu
 	}
 
 	err = validate.Many(
-		validateFuture("End", temp.End),
+		store.validateFuture("End", temp.End),
 		validateTimeRange("", temp.Start, temp.End),
 		validate.UUID("ScheduleID", scheduleID),
 		store.validateShifts(ctx, "Shifts", FixedShiftsPerTemporaryScheduleLimit, temp.Shifts, temp.Start, temp.End),
+		validateTags("Tags", temp.Tags),
 	)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if temp.Recurrence != nil {
+		err = validateRecurrence("Recurrence", temp.Recurrence, temp.End.Sub(temp.Start))
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// truncate to current timestamp
-	temp.TrimStart(time.Now())
-	return store.updateFixedShifts(ctx, tx, scheduleID, func(data *Data) error {
-		data.V1.TemporarySchedules = setFixedShifts(data.V1.TemporarySchedules, temp)
+	temp.TrimStart(store.clock.Now())
+
+	window := TimeRange{Start: temp.Start, End: temp.End}
+
+	if temp.Recurrence == nil {
+		return store.updateFixedShifts(ctx, tx, scheduleID, ifMatch, AuditOpSet, window, func(data *Data) error {
+			data.V1.TemporarySchedules = setFixedShifts(data.V1.TemporarySchedules, temp)
+			return nil
+		})
+	}
+
+	return store.updateFixedShifts(ctx, tx, scheduleID, ifMatch, AuditOpSet, window, func(data *Data) error {
+		rs := RecurringSchedule{Anchor: temp, Recurrence: *temp.Recurrence, MaterializedThrough: temp.Start}
+
+		occurrences := rs.expand(temp.Start, temp.Start.Add(recurrenceHorizon), FixedShiftsPerTemporaryScheduleLimit)
+		for _, occ := range occurrences {
+			data.V1.TemporarySchedules = setFixedShifts(data.V1.TemporarySchedules, occ)
+			if occ.End.After(rs.MaterializedThrough) {
+				rs.MaterializedThrough = occ.End
+			}
+		}
+		data.V1.RecurringSchedules = append(data.V1.RecurringSchedules, rs)
+
 		return nil
 	})
 }
 
-// ClearTemporarySchedules will clear out (or split, if needed) any defined TemporarySchedules that exist between the start and end time.
-func (store *Store) ClearTemporarySchedules(ctx context.Context, tx *sql.Tx, scheduleID string, start, end time.Time) error {
+// ClearTemporarySchedules will clear out (or split, if needed) any defined TemporarySchedules that
+// exist between the start and end time. It returns the resulting set of TemporarySchedules
+// (post-merge, post-user-filter) as TemporarySchedules would.
+func (store *Store) ClearTemporarySchedules(ctx context.Context, tx *sql.Tx, scheduleID string, start, end time.Time) ([]TemporarySchedule, error) {
+	return store.ClearTemporarySchedulesIfMatch(ctx, tx, scheduleID, start, end, -1)
+}
+
+// ClearTemporarySchedulesIfMatch behaves like ClearTemporarySchedules, but fails with
+// ErrVersionConflict if the schedule's stored Data.Version does not equal ifMatch. Passing a
+// negative ifMatch disables the check, matching ClearTemporarySchedules.
+func (store *Store) ClearTemporarySchedulesIfMatch(ctx context.Context, tx *sql.Tx, scheduleID string, start, end time.Time, ifMatch int) ([]TemporarySchedule, error) {
 	err := permission.LimitCheckAny(ctx, permission.User)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = validate.Many(
-		validateFuture("End", end),
+		store.validateFuture("End", end),
 		validateTimeRange("", start, end),
 		validate.UUID("ScheduleID", scheduleID),
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if time.Since(start) > 0 {
-		start = time.Now()
+	if store.clock.Now().After(start) {
+		start = store.clock.Now()
 	}
 
-	return store.updateFixedShifts(ctx, tx, scheduleID, func(data *Data) error {
+	return store.updateFixedShifts(ctx, tx, scheduleID, ifMatch, AuditOpClear, TimeRange{Start: start, End: end}, func(data *Data) error {
 		data.V1.TemporarySchedules = deleteFixedShifts(data.V1.TemporarySchedules, start, end)
+
+		// preserve the underlying rule for occurrences outside [start, end), appending to a
+		// freshly-allocated Excluded/RecurringSchedules slice so the audit "before" snapshot
+		// (which shares the prior backing array) isn't mutated in place.
+		recurring := make([]RecurringSchedule, len(data.V1.RecurringSchedules))
+		for i, rs := range data.V1.RecurringSchedules {
+			rs.Excluded = append(append([]TimeRange{}, rs.Excluded...), TimeRange{Start: start, End: end})
+			recurring[i] = rs
+		}
+		data.V1.RecurringSchedules = recurring
+
+		return nil
+	})
+}
+
+// TemporarySchedulesByTag returns the current set of TemporarySchedules for scheduleID
+// that are labeled with the given tag.
+func (store *Store) TemporarySchedulesByTag(ctx context.Context, tx *sql.Tx, scheduleID, tag string) ([]TemporarySchedule, error) {
+	all, err := store.TemporarySchedules(ctx, tx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []TemporarySchedule
+	for _, t := range all {
+		if hasTag(t.Tags, tag) {
+			result = append(result, t)
+		}
+	}
+
+	return result, nil
+}
+
+// ClearTemporarySchedulesByTag clears all TemporarySchedules for scheduleID that are
+// labeled with the given tag, regardless of their time range. This lets operators bulk-manage
+// overlapping coverage windows (e.g. all `incident-2024-q3` shifts) without knowing exact times.
+// Any RecurringSchedule whose Anchor carries the tag is dropped entirely, so a recurring rule
+// cleared this way does not resurface future occurrences under that tag. It returns the
+// resulting set of TemporarySchedules (post-merge, post-user-filter).
+func (store *Store) ClearTemporarySchedulesByTag(ctx context.Context, tx *sql.Tx, scheduleID, tag string) ([]TemporarySchedule, error) {
+	err := permission.LimitCheckAny(ctx, permission.User)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validate.UUID("ScheduleID", scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// determine the window actually being cleared, so the audit record reflects it instead
+	// of a zero-value TimeRange.
+	tagged, err := store.TemporarySchedulesByTag(ctx, tx, scheduleID, tag)
+	if err != nil {
+		return nil, err
+	}
+	var window TimeRange
+	for i, t := range tagged {
+		if i == 0 || t.Start.Before(window.Start) {
+			window.Start = t.Start
+		}
+		if t.End.After(window.End) {
+			window.End = t.End
+		}
+	}
+
+	return store.updateFixedShifts(ctx, tx, scheduleID, -1, AuditOpClear, window, func(data *Data) error {
+		data.V1.TemporarySchedules = withoutTag(data.V1.TemporarySchedules, tag)
+
+		recurring := make([]RecurringSchedule, 0, len(data.V1.RecurringSchedules))
+		for _, rs := range data.V1.RecurringSchedules {
+			if hasTag(rs.Anchor.Tags, tag) {
+				continue
+			}
+			recurring = append(recurring, rs)
+		}
+		data.V1.RecurringSchedules = recurring
+
 		return nil
 	})
 }
@@ -0,0 +1,69 @@
+package schedule
+
+import "time"
+
+// Shift represents a fixed assignment of a user to a schedule for a given period of time.
+type Shift struct {
+	UserID string
+	Start  time.Time
+	End    time.Time
+}
+
+// TemporarySchedule allows overriding the normal schedule rules with a fixed
+// set of shifts for a given period of time.
+type TemporarySchedule struct {
+	Start, End time.Time
+	Shifts     []Shift
+
+	// Tags are user-defined labels (e.g. `holiday-coverage`, `incident-2024-q3`) used to
+	// group and bulk-manage related TemporarySchedules.
+	Tags []string
+
+	// Recurrence, if set, causes SetTemporarySchedule to treat Start/Shifts/Tags as the
+	// first occurrence of a repeating TemporarySchedule, rather than a single fixed window.
+	Recurrence *Recurrence
+}
+
+// TrimStart will trim the start of the TemporarySchedule (and any contained Shifts) to `now`,
+// dropping any shifts that have already ended.
+func (temp *TemporarySchedule) TrimStart(now time.Time) {
+	if temp.Start.Before(now) {
+		temp.Start = now
+	}
+
+	shifts := temp.Shifts[:0]
+	for _, s := range temp.Shifts {
+		if !s.End.After(now) {
+			continue
+		}
+		if s.Start.Before(now) {
+			s.Start = now
+		}
+		shifts = append(shifts, s)
+	}
+	temp.Shifts = shifts
+}
+
+// hasTag returns true if tags contains tag.
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutTag returns a freshly-allocated copy of schedules with any entries labeled with
+// tag removed. It never reuses schedules' backing array, so callers that still hold a
+// reference to the original slice (e.g. for an audit "before" snapshot) are unaffected.
+func withoutTag(schedules []TemporarySchedule, tag string) []TemporarySchedule {
+	filtered := make([]TemporarySchedule, 0, len(schedules))
+	for _, t := range schedules {
+		if hasTag(t.Tags, tag) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered
+}
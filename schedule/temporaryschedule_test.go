@@ -0,0 +1,56 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasTag(t *testing.T) {
+	assert.True(t, hasTag([]string{"a", "b"}, "b"))
+	assert.False(t, hasTag([]string{"a", "b"}, "c"))
+	assert.False(t, hasTag(nil, "a"))
+}
+
+func TestWithoutTag_NoAliasing(t *testing.T) {
+	schedules := []TemporarySchedule{
+		{Start: tm(0), End: tm(1), Tags: []string{"keep"}},
+		{Start: tm(1), End: tm(2), Tags: []string{"drop"}},
+		{Start: tm(2), End: tm(3), Tags: []string{"keep"}},
+	}
+
+	// simulate the audit "before" snapshot taken prior to filtering
+	before := schedules
+
+	result := withoutTag(schedules, "drop")
+
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, []string{"keep"}, result[0].Tags)
+		assert.Equal(t, []string{"keep"}, result[1].Tags)
+	}
+
+	// the snapshot must be untouched by the filter -- it shares no backing array with result
+	if assert.Len(t, before, 3) {
+		assert.Equal(t, []string{"keep"}, before[0].Tags)
+		assert.Equal(t, []string{"drop"}, before[1].Tags)
+		assert.Equal(t, []string{"keep"}, before[2].Tags)
+	}
+}
+
+func TestTrimStart(t *testing.T) {
+	temp := TemporarySchedule{
+		Start: tm(0), End: tm(4),
+		Shifts: []Shift{
+			{UserID: "u1", Start: tm(0), End: tm(1)},
+			{UserID: "u2", Start: tm(1), End: tm(3)},
+		},
+	}
+
+	temp.TrimStart(tm(2))
+
+	assert.Equal(t, tm(2), temp.Start)
+	if assert.Len(t, temp.Shifts, 1) {
+		assert.Equal(t, tm(2), temp.Shifts[0].Start)
+		assert.Equal(t, tm(3), temp.Shifts[0].End)
+	}
+}
@@ -0,0 +1,58 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/target/goalert/validation"
+	"github.com/target/goalert/validation/validate"
+)
+
+func validateTimeRange(fieldName string, start, end time.Time) error {
+	if !end.After(start) {
+		name := fieldName
+		if name == "" {
+			name = "End"
+		}
+		return validation.NewFieldError(name, "must be after Start")
+	}
+	return nil
+}
+
+// validateShifts ensures the provided shifts are valid for a TemporarySchedule covering
+// start to end, and that there are no more than max of them.
+func (store *Store) validateShifts(ctx context.Context, fieldName string, max int, shifts []Shift, start, end time.Time) error {
+	if len(shifts) > max {
+		return validation.NewFieldError(fieldName, fmt.Sprintf("must not contain more than %d shifts", max))
+	}
+
+	for i, s := range shifts {
+		name := fmt.Sprintf("%s[%d]", fieldName, i)
+		err := validate.Many(
+			validate.UUID(name+".UserID", s.UserID),
+			validateTimeRange(name, s.Start, s.End),
+		)
+		if err != nil {
+			return err
+		}
+		if s.Start.Before(start) || s.End.After(end) {
+			return validation.NewFieldError(name, "must be within Start and End of the TemporarySchedule")
+		}
+	}
+
+	return nil
+}
+
+// validateTags ensures tags are non-empty and reasonably sized.
+func validateTags(fieldName string, tags []string) error {
+	for i, tag := range tags {
+		err := validate.Many(
+			validate.Text(fmt.Sprintf("%s[%d]", fieldName, i), tag, 1, 35),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}